@@ -0,0 +1,30 @@
+package git
+
+import (
+	"io"
+
+	"gopkg.in/src-d/go-git.v2/core"
+)
+
+// Blob is the content of a single file, decoded from a core.Object of type
+// core.BlobObject.
+type Blob struct {
+	Hash core.Hash
+	Size int64
+
+	obj core.Object
+}
+
+// Decode transforms a core.Object into a Blob struct.
+func (b *Blob) Decode(o core.Object) error {
+	b.Hash = o.Hash()
+	b.Size = o.Size()
+	b.obj = o
+
+	return nil
+}
+
+// Reader returns a reader allowing access to the blob's content.
+func (b *Blob) Reader() io.Reader {
+	return b.obj.Reader()
+}
@@ -0,0 +1,85 @@
+// Package core defines the basic building blocks shared by every other
+// package in go-git: the object hash, the four git object kinds, and the
+// storage-agnostic Object/ObjectStorage interfaces that Repository, Tree
+// and Blob are built on top of.
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Hash is the SHA1 object id of a git object.
+type Hash [20]byte
+
+func (h Hash) String() string {
+	return fmt.Sprintf("%x", h[:])
+}
+
+// ObjectType identifies the kind of content a git object holds.
+type ObjectType int8
+
+// The four object types git knows about.
+const (
+	CommitObject ObjectType = iota + 1
+	TreeObject
+	BlobObject
+	TagObject
+)
+
+func (t ObjectType) String() string {
+	switch t {
+	case CommitObject:
+		return "commit"
+	case TreeObject:
+		return "tree"
+	case BlobObject:
+		return "blob"
+	case TagObject:
+		return "tag"
+	default:
+		return "unknown"
+	}
+}
+
+// Object is a single git object: a blob, tree, commit or tag, addressed by
+// its Hash. Reader returns the object's content, with any type-specific
+// header already stripped.
+type Object interface {
+	Hash() Hash
+	Type() ObjectType
+	Size() int64
+	Reader() io.Reader
+}
+
+// ObjectStorage is the read side of a repository's object database -
+// Repository.Storage is one of these.
+type ObjectStorage interface {
+	Get(Hash) (Object, bool)
+}
+
+type object struct {
+	hash    Hash
+	kind    ObjectType
+	content []byte
+}
+
+// NewObject returns an Object of the given kind, identified by hash, whose
+// Reader yields content.
+func NewObject(hash Hash, kind ObjectType, content []byte) Object {
+	return &object{hash: hash, kind: kind, content: content}
+}
+
+// NewObjectFromBytes returns a blob Object identified by hash, whose
+// Reader yields content.
+func NewObjectFromBytes(hash Hash, content []byte) (Object, error) {
+	return NewObject(hash, BlobObject, content), nil
+}
+
+func (o *object) Hash() Hash      { return o.hash }
+func (o *object) Type() ObjectType { return o.kind }
+func (o *object) Size() int64     { return int64(len(o.content)) }
+func (o *object) Reader() io.Reader {
+	return bytes.NewReader(o.content)
+}
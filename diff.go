@@ -0,0 +1,350 @@
+package git
+
+import (
+	"errors"
+	"hash/fnv"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/src-d/go-git.v2/core"
+)
+
+// errNotABlob is returned internally by DetectRenames' shingling when a
+// hash turns out not to identify a blob.
+var errNotABlob = errors.New("git: object is not a blob")
+
+// ChangeAction is the kind of change a Change describes.
+type ChangeAction int
+
+// The actions a Change can describe. Rename and Copy are only ever
+// produced by DetectRenames, never by Diff itself.
+const (
+	Insert ChangeAction = iota
+	Delete
+	Modify
+	Rename
+	Copy
+)
+
+func (a ChangeAction) String() string {
+	switch a {
+	case Insert:
+		return "insert"
+	case Delete:
+		return "delete"
+	case Modify:
+		return "modify"
+	case Rename:
+		return "rename"
+	case Copy:
+		return "copy"
+	default:
+		return "unknown"
+	}
+}
+
+// Change describes a single difference between two trees. From is the zero
+// TreeEntry for Insert, To is the zero TreeEntry for Delete; both are set
+// otherwise.
+type Change struct {
+	Action ChangeAction
+	From   TreeEntry
+	To     TreeEntry
+}
+
+// TreeDiffIter iterates over the Changes produced by Tree.Diff, computing
+// them lazily as it goes so a diff between two huge trees only pays for the
+// subtrees that actually changed.
+type TreeDiffIter struct {
+	ch chan *Change
+}
+
+// Next returns the next Change, or io.EOF once the diff is exhausted.
+func (i *TreeDiffIter) Next() (*Change, error) {
+	c, ok := <-i.ch
+	if !ok {
+		return nil, io.EOF
+	}
+
+	return c, nil
+}
+
+// Diff returns a TreeDiffIter streaming the Changes between t and other. It
+// merge-walks the two trees' sorted entries, only recursing into a subtree
+// when its hash differs between t and other - the same O(changed) path
+// `git diff` itself takes, rather than hashing every blob in both trees.
+func (t *Tree) Diff(other *Tree) (*TreeDiffIter, error) {
+	ch := make(chan *Change, 1)
+
+	go func() {
+		defer close(ch)
+		diffTrees(t, other, "", ch)
+	}()
+
+	return &TreeDiffIter{ch: ch}, nil
+}
+
+func diffTrees(a, b *Tree, base string, ch chan *Change) {
+	for _, name := range mergedEntryNames(a, b) {
+		ea, inA := a.Entries[name]
+		eb, inB := b.Entries[name]
+		path := filepath.Join(base, name)
+
+		switch {
+		case inA && !inB:
+			if treeA, err := a.dir(name); err == nil {
+				emitDeletes(treeA, path, ch)
+				continue
+			}
+
+			ea.Name = path
+			ch <- &Change{Action: Delete, From: ea}
+		case !inA && inB:
+			if treeB, err := b.dir(name); err == nil {
+				emitInserts(treeB, path, ch)
+				continue
+			}
+
+			eb.Name = path
+			ch <- &Change{Action: Insert, To: eb}
+		case ea.Hash == eb.Hash:
+			// Unchanged subtree or blob - nothing to report, and nothing
+			// underneath it needs walking either.
+		default:
+			treeA, errA := a.dir(name)
+			treeB, errB := b.dir(name)
+
+			switch {
+			case errA == nil && errB == nil:
+				diffTrees(treeA, treeB, path, ch)
+			case errA == nil && errB != nil:
+				// Type change: a directory became a blob (or a gitlink).
+				// Report everything that used to live under it as deleted
+				// instead of collapsing the whole subtree into one Modify
+				// and silently dropping it from the diff.
+				emitDeletes(treeA, path, ch)
+				eb.Name = path
+				ch <- &Change{Action: Insert, To: eb}
+			case errA != nil && errB == nil:
+				ea.Name = path
+				ch <- &Change{Action: Delete, From: ea}
+				emitInserts(treeB, path, ch)
+			default:
+				ea.Name, eb.Name = path, path
+				ch <- &Change{Action: Modify, From: ea, To: eb}
+			}
+		}
+	}
+}
+
+// emitDeletes reports every blob under t as Delete, recursing through
+// subtrees, for a subtree that was removed outright or whose replacement is
+// a blob rather than another tree.
+func emitDeletes(t *Tree, base string, ch chan *Change) {
+	for name, entry := range t.Entries {
+		path := filepath.Join(base, name)
+
+		if sub, err := t.dir(name); err == nil {
+			emitDeletes(sub, path, ch)
+			continue
+		}
+
+		entry.Name = path
+		ch <- &Change{Action: Delete, From: entry}
+	}
+}
+
+// emitInserts is emitDeletes' mirror image, for a subtree that was added
+// outright or whose predecessor was a blob rather than a tree.
+func emitInserts(t *Tree, base string, ch chan *Change) {
+	for name, entry := range t.Entries {
+		path := filepath.Join(base, name)
+
+		if sub, err := t.dir(name); err == nil {
+			emitInserts(sub, path, ch)
+			continue
+		}
+
+		entry.Name = path
+		ch <- &Change{Action: Insert, To: entry}
+	}
+}
+
+func mergedEntryNames(a, b *Tree) []string {
+	seen := make(map[string]bool, len(a.Entries)+len(b.Entries))
+	names := make([]string, 0, len(a.Entries)+len(b.Entries))
+
+	for name := range a.Entries {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for name := range b.Entries {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// DefaultRenameThreshold is the similarity score (see DetectRenames) above
+// which an Insert/Delete pair is considered a rename or copy rather than
+// two unrelated changes.
+const DefaultRenameThreshold = 0.5
+
+// shingleSet is the set of rolling-hash shingles used to approximate blob
+// similarity without diffing full contents.
+type shingleSet map[uint64]struct{}
+
+const shingleWindow = 64
+
+// shingle hashes every shingleWindow-byte window of content into a set,
+// so similarity between two blobs can be estimated in O(n) without an
+// actual byte-level diff.
+func shingle(content []byte) shingleSet {
+	set := make(shingleSet)
+
+	if len(content) <= shingleWindow {
+		h := fnv.New64a()
+		h.Write(content)
+		set[h.Sum64()] = struct{}{}
+		return set
+	}
+
+	for i := 0; i+shingleWindow <= len(content); i++ {
+		h := fnv.New64a()
+		h.Write(content[i : i+shingleWindow])
+		set[h.Sum64()] = struct{}{}
+	}
+
+	return set
+}
+
+// jaccard returns |a ∩ b| / |a ∪ b|, the similarity score DetectRenames
+// compares against its threshold.
+func jaccard(a, b shingleSet) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	intersection := 0
+	for h := range a {
+		if _, ok := b[h]; ok {
+			intersection++
+		}
+	}
+
+	union := len(a) + len(b) - intersection
+	if union == 0 {
+		return 0
+	}
+
+	return float64(intersection) / float64(union)
+}
+
+// DetectRenames rewrites changes, replacing Insert/Delete pairs whose blob
+// content is similar enough into Rename changes (or Copy, for an Insert
+// that matches a Delete already claimed by an earlier Rename). threshold is
+// the minimum Jaccard similarity (see jaccard) to consider a pair related;
+// 0 selects DefaultRenameThreshold.
+func DetectRenames(r *Repository, changes []Change, threshold float64) ([]Change, error) {
+	if threshold <= 0 {
+		threshold = DefaultRenameThreshold
+	}
+
+	var added, deleted []int
+	for i, c := range changes {
+		switch c.Action {
+		case Insert:
+			added = append(added, i)
+		case Delete:
+			deleted = append(deleted, i)
+		}
+	}
+
+	shingles := map[core.Hash]shingleSet{}
+	shinglesFor := func(hash core.Hash) (shingleSet, error) {
+		if s, ok := shingles[hash]; ok {
+			return s, nil
+		}
+
+		obj, ok := r.Storage.Get(hash)
+		if !ok {
+			return nil, ErrFileNotFound
+		}
+
+		if obj.Type() != core.BlobObject {
+			// A tree (or other non-blob) that was added/deleted wholesale;
+			// emitDeletes/emitInserts already expanded it into its blobs,
+			// so this hash itself has nothing meaningful to compare.
+			return nil, errNotABlob
+		}
+
+		blob := &Blob{}
+		blob.Decode(obj)
+
+		content, err := ioutil.ReadAll(blob.Reader())
+		if err != nil {
+			return nil, err
+		}
+
+		s := shingle(content)
+		shingles[hash] = s
+		return s, nil
+	}
+
+	out := append([]Change(nil), changes...)
+	matches := make(map[int]int, len(deleted)) // deleted index -> times matched
+
+	for _, ai := range added {
+		addedShingles, err := shinglesFor(changes[ai].To.Hash)
+		if err != nil {
+			continue
+		}
+
+		best, bestScore := -1, threshold
+		for _, di := range deleted {
+			deletedShingles, err := shinglesFor(changes[di].From.Hash)
+			if err != nil {
+				continue
+			}
+
+			if score := jaccard(addedShingles, deletedShingles); score >= bestScore {
+				bestScore = score
+				best = di
+			}
+		}
+
+		if best == -1 {
+			continue
+		}
+
+		action := Rename
+		if matches[best] > 0 {
+			action = Copy
+		}
+		matches[best]++
+
+		out[ai] = Change{Action: action, From: changes[best].From, To: changes[ai].To}
+	}
+
+	result := out[:0]
+	for i, c := range out {
+		// The first match consumes its Delete into a Rename; later matches
+		// against the same source become Copy changes and leave it be.
+		if c.Action == Delete && matches[i] > 0 {
+			continue
+		}
+
+		result = append(result, c)
+	}
+
+	return result, nil
+}
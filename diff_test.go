@@ -0,0 +1,318 @@
+package git
+
+import (
+	"sort"
+	"testing"
+)
+
+func collectChanges(t *testing.T, iter *TreeDiffIter) []Change {
+	t.Helper()
+
+	var changes []Change
+	for {
+		c, err := iter.Next()
+		if err != nil {
+			break
+		}
+		changes = append(changes, *c)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changePath(changes[i]) < changePath(changes[j])
+	})
+
+	return changes
+}
+
+func changePath(c Change) string {
+	if c.To.Name != "" {
+		return c.To.Name
+	}
+	return c.From.Name
+}
+
+func TestDiffFlatChanges(t *testing.T) {
+	storage := memStorage{}
+	unchangedBlob := testHash(1)
+	deletedBlob := testHash(2)
+	modifiedOldBlob := testHash(3)
+	modifiedNewBlob := testHash(4)
+	insertedBlob := testHash(5)
+	storage.putBlob(unchangedBlob, []byte("same"))
+	storage.putBlob(deletedBlob, []byte("gone"))
+	storage.putBlob(modifiedOldBlob, []byte("old"))
+	storage.putBlob(modifiedNewBlob, []byte("new"))
+	storage.putBlob(insertedBlob, []byte("added"))
+
+	a := &Tree{r: &Repository{Storage: storage}, Entries: map[string]TreeEntry{
+		"same.txt":     {Name: "same.txt", Mode: 0100644, Hash: unchangedBlob},
+		"deleted.txt":  {Name: "deleted.txt", Mode: 0100644, Hash: deletedBlob},
+		"modified.txt": {Name: "modified.txt", Mode: 0100644, Hash: modifiedOldBlob},
+	}}
+
+	b := &Tree{r: &Repository{Storage: storage}, Entries: map[string]TreeEntry{
+		"same.txt":     {Name: "same.txt", Mode: 0100644, Hash: unchangedBlob},
+		"modified.txt": {Name: "modified.txt", Mode: 0100644, Hash: modifiedNewBlob},
+		"inserted.txt": {Name: "inserted.txt", Mode: 0100644, Hash: insertedBlob},
+	}}
+
+	iter, err := a.Diff(b)
+	if err != nil {
+		t.Fatalf("Diff: %s", err)
+	}
+
+	changes := collectChanges(t, iter)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes (delete, modify, insert), got %d: %+v", len(changes), changes)
+	}
+
+	if changes[0].Action != Delete || changes[0].From.Name != "deleted.txt" {
+		t.Fatalf("expected deleted.txt to be Delete, got %+v", changes[0])
+	}
+	if changes[1].Action != Insert || changes[1].To.Name != "inserted.txt" {
+		t.Fatalf("expected inserted.txt to be Insert, got %+v", changes[1])
+	}
+	if changes[2].Action != Modify || changes[2].From.Name != "modified.txt" {
+		t.Fatalf("expected modified.txt to be Modify, got %+v", changes[2])
+	}
+}
+
+func TestDiffRecursesIntoChangedSubtrees(t *testing.T) {
+	storage := memStorage{}
+	oldBlob, newBlob := testHash(1), testHash(2)
+	storage.putBlob(oldBlob, []byte("old"))
+	storage.putBlob(newBlob, []byte("new"))
+
+	oldSubtree, newSubtree := testHash(3), testHash(4)
+	storage.putTree(oldSubtree, map[string]TreeEntry{
+		"f.txt": {Name: "f.txt", Mode: 0100644, Hash: oldBlob},
+	})
+	storage.putTree(newSubtree, map[string]TreeEntry{
+		"f.txt": {Name: "f.txt", Mode: 0100644, Hash: newBlob},
+	})
+
+	a := &Tree{r: &Repository{Storage: storage}, Entries: map[string]TreeEntry{
+		"sub": {Name: "sub", Mode: 040000, Hash: oldSubtree},
+	}}
+	b := &Tree{r: &Repository{Storage: storage}, Entries: map[string]TreeEntry{
+		"sub": {Name: "sub", Mode: 040000, Hash: newSubtree},
+	}}
+
+	iter, err := a.Diff(b)
+	if err != nil {
+		t.Fatalf("Diff: %s", err)
+	}
+
+	changes := collectChanges(t, iter)
+	if len(changes) != 1 || changes[0].Action != Modify || changes[0].From.Name != "sub/f.txt" {
+		t.Fatalf("expected a single Modify at sub/f.txt, got %+v", changes)
+	}
+}
+
+func TestDiffWholeSubtreeInsertAndDeleteExpandPerBlob(t *testing.T) {
+	storage := memStorage{}
+	blobOne, blobTwo := testHash(1), testHash(2)
+	storage.putBlob(blobOne, []byte("one"))
+	storage.putBlob(blobTwo, []byte("two"))
+
+	subtree := testHash(3)
+	storage.putTree(subtree, map[string]TreeEntry{
+		"one.txt": {Name: "one.txt", Mode: 0100644, Hash: blobOne},
+		"two.txt": {Name: "two.txt", Mode: 0100644, Hash: blobTwo},
+	})
+
+	empty := &Tree{r: &Repository{Storage: storage}, Entries: map[string]TreeEntry{}}
+	withSubtree := &Tree{r: &Repository{Storage: storage}, Entries: map[string]TreeEntry{
+		"dir": {Name: "dir", Mode: 040000, Hash: subtree},
+	}}
+
+	insertIter, err := empty.Diff(withSubtree)
+	if err != nil {
+		t.Fatalf("Diff: %s", err)
+	}
+
+	inserts := collectChanges(t, insertIter)
+	if len(inserts) != 2 {
+		t.Fatalf("expected the whole subtree to expand into 2 Inserts, not one directory-level change, got %+v", inserts)
+	}
+	for _, c := range inserts {
+		if c.Action != Insert {
+			t.Fatalf("expected Insert, got %+v", c)
+		}
+	}
+
+	deleteIter, err := withSubtree.Diff(empty)
+	if err != nil {
+		t.Fatalf("Diff: %s", err)
+	}
+
+	deletes := collectChanges(t, deleteIter)
+	if len(deletes) != 2 {
+		t.Fatalf("expected the whole subtree to expand into 2 Deletes, not one directory-level change, got %+v", deletes)
+	}
+	for _, c := range deletes {
+		if c.Action != Delete {
+			t.Fatalf("expected Delete, got %+v", c)
+		}
+	}
+}
+
+func TestDiffTypeChangeExpandsSubtree(t *testing.T) {
+	storage := memStorage{}
+	blob := testHash(1)
+	storage.putBlob(blob, []byte("content"))
+
+	subtree := testHash(2)
+	storage.putTree(subtree, map[string]TreeEntry{
+		"f.txt": {Name: "f.txt", Mode: 0100644, Hash: blob},
+	})
+
+	replacement := testHash(3)
+	storage.putBlob(replacement, []byte("now a file"))
+
+	a := &Tree{r: &Repository{Storage: storage}, Entries: map[string]TreeEntry{
+		"thing": {Name: "thing", Mode: 040000, Hash: subtree},
+	}}
+	b := &Tree{r: &Repository{Storage: storage}, Entries: map[string]TreeEntry{
+		"thing": {Name: "thing", Mode: 0100644, Hash: replacement},
+	}}
+
+	iter, err := a.Diff(b)
+	if err != nil {
+		t.Fatalf("Diff: %s", err)
+	}
+
+	changes := collectChanges(t, iter)
+	if len(changes) != 2 {
+		t.Fatalf("expected a Delete for the old blob under thing/ plus an Insert for the new file, got %+v", changes)
+	}
+	if changes[0].Action != Insert || changes[0].To.Name != "thing" {
+		t.Fatalf("expected Insert at thing, got %+v", changes[0])
+	}
+	if changes[1].Action != Delete || changes[1].From.Name != "thing/f.txt" {
+		t.Fatalf("expected Delete at thing/f.txt, got %+v", changes[1])
+	}
+}
+
+func TestJaccardIdenticalContent(t *testing.T) {
+	content := []byte("the quick brown fox jumps over the lazy dog, over and over again")
+
+	a := shingle(content)
+	b := shingle(content)
+
+	if score := jaccard(a, b); score != 1 {
+		t.Fatalf("expected identical content to score 1, got %v", score)
+	}
+}
+
+func TestJaccardDisjointContent(t *testing.T) {
+	a := shingle(repeat('a', 200))
+	b := shingle(repeat('b', 200))
+
+	if score := jaccard(a, b); score != 0 {
+		t.Fatalf("expected disjoint content to score 0, got %v", score)
+	}
+}
+
+// TestJaccardIdenticalContentClearsDefaultThreshold checks that jaccard
+// scores identical content at or above DefaultRenameThreshold; see
+// TestDetectRenamesThresholdIsInclusive for the boundary check against
+// DetectRenames proper.
+func TestJaccardIdenticalContentClearsDefaultThreshold(t *testing.T) {
+	content := repeat('a', 200)
+	a := shingle(content)
+	b := shingle(content)
+
+	score := jaccard(a, b)
+	if score < DefaultRenameThreshold {
+		t.Fatalf("expected identical content to clear the default threshold, got %v", score)
+	}
+}
+
+// TestDetectRenamesThresholdIsInclusive guards the >= in DetectRenames
+// itself: a pair scoring exactly the configured threshold must be
+// promoted to a Rename, not left behind as an unrelated Insert/Delete.
+func TestDetectRenamesThresholdIsInclusive(t *testing.T) {
+	storage := memStorage{}
+	// Longer than shingleWindow, so the shared prefix yields shared shingles.
+	oldContent := []byte("the quick brown fox jumps over the lazy dog, then trots off across the meadow towards the river bank and the old mill, chasing the dog")
+	newContent := []byte("the quick brown fox jumps over the lazy dog, then trots off across the meadow towards the river bank and the old mill, chasing the cat")
+	oldHash, newHash := testHash(1), testHash(2)
+	storage.putBlob(oldHash, oldContent)
+	storage.putBlob(newHash, newContent)
+
+	score := jaccard(shingle(oldContent), shingle(newContent))
+	if score <= 0 || score >= 1 {
+		t.Fatalf("expected a partial similarity score to exercise the boundary, got %v", score)
+	}
+
+	changes := []Change{
+		{Action: Delete, From: TreeEntry{Name: "old.txt", Hash: oldHash}},
+		{Action: Insert, To: TreeEntry{Name: "new.txt", Hash: newHash}},
+	}
+
+	repo := &Repository{Storage: storage}
+
+	atThreshold, err := DetectRenames(repo, changes, score)
+	if err != nil {
+		t.Fatalf("DetectRenames: %s", err)
+	}
+	if len(atThreshold) != 1 || atThreshold[0].Action != Rename {
+		t.Fatalf("expected a pair scoring exactly the threshold to be promoted to a Rename, got %+v", atThreshold)
+	}
+
+	aboveScore, err := DetectRenames(repo, changes, score+0.01)
+	if err != nil {
+		t.Fatalf("DetectRenames: %s", err)
+	}
+	if len(aboveScore) != 2 {
+		t.Fatalf("expected a pair scoring below the threshold to be left as Insert/Delete, got %+v", aboveScore)
+	}
+}
+
+func TestDetectRenamesSkipsNonBlobHashes(t *testing.T) {
+	storage := memStorage{}
+	treeHash := testHash(1)
+	storage.putTree(treeHash, map[string]TreeEntry{})
+
+	blobHash := testHash(2)
+	storage.putBlob(blobHash, []byte("content"))
+
+	changes := []Change{
+		{Action: Delete, From: TreeEntry{Name: "old-dir", Hash: treeHash}},
+		{Action: Insert, To: TreeEntry{Name: "new.txt", Hash: blobHash}},
+	}
+
+	repo := &Repository{Storage: storage}
+
+	out, err := DetectRenames(repo, changes, DefaultRenameThreshold)
+	if err != nil {
+		t.Fatalf("DetectRenames: %s", err)
+	}
+
+	if len(out) != 2 {
+		t.Fatalf("expected the tree hash to be skipped rather than scored as a rename source, got %+v", out)
+	}
+	for _, c := range out {
+		if c.Action == Rename || c.Action == Copy {
+			t.Fatalf("expected no rename/copy to be produced against a non-blob hash, got %+v", out)
+		}
+	}
+}
+
+func TestJaccardEmptyContent(t *testing.T) {
+	a := shingle(nil)
+	b := shingle(nil)
+
+	if score := jaccard(a, b); score != 1 {
+		t.Fatalf("expected two empty blobs to score 1, got %v", score)
+	}
+}
+
+func repeat(b byte, n int) []byte {
+	buf := make([]byte, n)
+	for i := range buf {
+		buf[i] = b
+	}
+	return buf
+}
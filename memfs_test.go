@@ -0,0 +1,138 @@
+package git
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/src-d/go-billy.v4"
+)
+
+// memFS is a minimal in-memory billy.Filesystem, just enough to exercise
+// Tree.Checkout/Worktree.Checkout without touching the real filesystem.
+// Only the handful of methods Checkout actually calls are meaningfully
+// implemented; the rest exist solely to satisfy billy.Filesystem.
+type memFS struct {
+	files    map[string]*memFile
+	symlinks map[string]string
+}
+
+func newMemFS() *memFS {
+	return &memFS{
+		files:    make(map[string]*memFile),
+		symlinks: make(map[string]string),
+	}
+}
+
+type memFile struct {
+	name string
+	mode os.FileMode
+	buf  bytes.Buffer
+}
+
+func (f *memFile) Name() string                    { return f.name }
+func (f *memFile) Write(p []byte) (int, error)     { return f.buf.Write(p) }
+func (f *memFile) Read(p []byte) (int, error)      { return f.buf.Read(p) }
+func (f *memFile) ReadAt(p []byte, off int64) (int, error) {
+	return bytes.NewReader(f.buf.Bytes()).ReadAt(p, off)
+}
+func (f *memFile) Close() error                    { return nil }
+func (f *memFile) Seek(int64, int) (int64, error)  { return 0, nil }
+func (f *memFile) Lock() error                     { return nil }
+func (f *memFile) Unlock() error                   { return nil }
+func (f *memFile) Truncate(int64) error            { return nil }
+
+func (fs *memFS) Create(filename string) (billy.File, error) {
+	return fs.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+func (fs *memFS) Open(filename string) (billy.File, error) {
+	f, ok := fs.files[filename]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return f, nil
+}
+
+func (fs *memFS) OpenFile(filename string, flag int, perm os.FileMode) (billy.File, error) {
+	f := &memFile{name: filename, mode: perm}
+	fs.files[filename] = f
+	return f, nil
+}
+
+func (fs *memFS) Stat(filename string) (os.FileInfo, error) {
+	f, ok := fs.files[filename]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return memFileInfo{f}, nil
+}
+
+func (fs *memFS) Rename(oldpath, newpath string) error {
+	f, ok := fs.files[oldpath]
+	if !ok {
+		return os.ErrNotExist
+	}
+	delete(fs.files, oldpath)
+	f.name = newpath
+	fs.files[newpath] = f
+	return nil
+}
+
+func (fs *memFS) Remove(filename string) error {
+	delete(fs.files, filename)
+	return nil
+}
+
+func (fs *memFS) Join(elem ...string) string {
+	return filepath.Join(elem...)
+}
+
+func (fs *memFS) TempFile(dir, prefix string) (billy.File, error) {
+	return fs.Create(filepath.Join(dir, prefix))
+}
+
+func (fs *memFS) ReadDir(path string) ([]os.FileInfo, error) {
+	return nil, nil
+}
+
+func (fs *memFS) MkdirAll(filename string, perm os.FileMode) error {
+	return nil
+}
+
+func (fs *memFS) Lstat(filename string) (os.FileInfo, error) {
+	return fs.Stat(filename)
+}
+
+func (fs *memFS) Symlink(target, link string) error {
+	fs.symlinks[link] = target
+	return nil
+}
+
+func (fs *memFS) Readlink(link string) (string, error) {
+	target, ok := fs.symlinks[link]
+	if !ok {
+		return "", os.ErrNotExist
+	}
+	return target, nil
+}
+
+func (fs *memFS) Chroot(path string) (billy.Filesystem, error) {
+	return fs, nil
+}
+
+func (fs *memFS) Root() string {
+	return "/"
+}
+
+type memFileInfo struct {
+	f *memFile
+}
+
+func (i memFileInfo) Name() string       { return filepath.Base(i.f.name) }
+func (i memFileInfo) Size() int64        { return int64(i.f.buf.Len()) }
+func (i memFileInfo) Mode() os.FileMode  { return i.f.mode }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return false }
+func (i memFileInfo) Sys() interface{}   { return nil }
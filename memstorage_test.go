@@ -0,0 +1,47 @@
+package git
+
+import (
+	"fmt"
+
+	"gopkg.in/src-d/go-git.v2/core"
+)
+
+// memStorage is a minimal in-memory core.ObjectStorage, so Tree/Repository
+// tests don't need a real storage.Backend.
+type memStorage map[core.Hash]core.Object
+
+func (s memStorage) Get(hash core.Hash) (core.Object, bool) {
+	obj, ok := s[hash]
+	return obj, ok
+}
+
+func (s memStorage) put(obj core.Object) {
+	s[obj.Hash()] = obj
+}
+
+// testHash builds a distinct, deterministic core.Hash from a small int, so
+// tests can refer to objects without hand-writing 20 bytes of hex.
+func testHash(n byte) core.Hash {
+	var h core.Hash
+	h[19] = n
+	return h
+}
+
+// putBlob stores content as a blob under hash in s.
+func (s memStorage) putBlob(hash core.Hash, content []byte) {
+	s.put(core.NewObject(hash, core.BlobObject, content))
+}
+
+// putTree encodes entries in git's tree object format and stores the
+// result under hash, mirroring how Tree.Decode expects to read it back.
+func (s memStorage) putTree(hash core.Hash, entries map[string]TreeEntry) {
+	var content []byte
+	for name, entry := range entries {
+		content = append(content, []byte(fmt.Sprintf("%o ", entry.Mode))...)
+		content = append(content, []byte(name)...)
+		content = append(content, 0)
+		content = append(content, entry.Hash[:]...)
+	}
+
+	s.put(core.NewObject(hash, core.TreeObject, content))
+}
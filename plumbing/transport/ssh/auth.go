@@ -0,0 +1,135 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// AuthMethod is the common interface implemented by every SSH
+// authentication mechanism accepted by Client. It extends
+// transport.AuthMethod with the pieces needed to dial the connection.
+type AuthMethod interface {
+	transport.AuthMethod
+	// clientConfig returns the ssh.ClientConfig used to authenticate.
+	clientConfig() *ssh.ClientConfig
+}
+
+// SSHAgentAuth authenticates against the SSH agent reachable through
+// SSH_AUTH_SOCK.
+type SSHAgentAuth struct {
+	User    string
+	Signers []ssh.Signer
+}
+
+// NewSSHAgentAuth returns an AuthMethod that authenticates as user using
+// the keys held by the running SSH agent.
+func NewSSHAgentAuth(user string) (AuthMethod, error) {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, errors.New("SSH_AUTH_SOCK env var not set, can't connect to ssh-agent")
+	}
+
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		return nil, err
+	}
+
+	signers, err := agent.NewClient(conn).Signers()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SSHAgentAuth{User: user, Signers: signers}, nil
+}
+
+// Name implements transport.AuthMethod.
+func (a *SSHAgentAuth) Name() string { return "ssh-agent-auth" }
+
+func (a *SSHAgentAuth) String() string {
+	return fmt.Sprintf("user: %s, name: %s", a.User, a.Name())
+}
+
+func (a *SSHAgentAuth) clientConfig() *ssh.ClientConfig {
+	return &ssh.ClientConfig{
+		User: a.User,
+		Auth: []ssh.AuthMethod{ssh.PublicKeys(a.Signers...)},
+	}
+}
+
+// PublicKeys authenticates with a single private key, for environments
+// (such as many CI runners) with no SSH_AUTH_SOCK to fall back on.
+type PublicKeys struct {
+	User   string
+	Signer ssh.Signer
+}
+
+// PubKeyFromFile reads and parses a private key from path, decrypting it
+// with passphrase if it is encrypted (pass "" for an unencrypted key), and
+// returns an AuthMethod for user backed by it.
+func PubKeyFromFile(user, path, passphrase string) (AuthMethod, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var signer ssh.Signer
+	if passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(content, []byte(passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(content)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublicKeys{User: user, Signer: signer}, nil
+}
+
+// Name implements transport.AuthMethod.
+func (a *PublicKeys) Name() string { return "ssh-public-keys" }
+
+func (a *PublicKeys) String() string {
+	return fmt.Sprintf("user: %s, name: %s", a.User, a.Name())
+}
+
+func (a *PublicKeys) clientConfig() *ssh.ClientConfig {
+	return &ssh.ClientConfig{
+		User: a.User,
+		Auth: []ssh.AuthMethod{ssh.PublicKeys(a.Signer)},
+	}
+}
+
+// PasswordAuth authenticates with a plain username/password pair.
+type PasswordAuth struct {
+	User     string
+	Password string
+}
+
+// Password returns an AuthMethod that authenticates with a plain
+// username/password pair, for servers that allow it instead of (or in
+// addition to) public key auth.
+func Password(user, password string) AuthMethod {
+	return &PasswordAuth{User: user, Password: password}
+}
+
+// Name implements transport.AuthMethod.
+func (a *PasswordAuth) Name() string { return "ssh-password-auth" }
+
+func (a *PasswordAuth) String() string {
+	return fmt.Sprintf("user: %s, name: %s", a.User, a.Name())
+}
+
+func (a *PasswordAuth) clientConfig() *ssh.ClientConfig {
+	return &ssh.ClientConfig{
+		User: a.User,
+		Auth: []ssh.AuthMethod{ssh.Password(a.Password)},
+	}
+}
@@ -0,0 +1,27 @@
+package ssh
+
+import "testing"
+
+func TestPubKeyFromFileMissing(t *testing.T) {
+	if _, err := PubKeyFromFile("git", "testdata/does-not-exist", ""); err == nil {
+		t.Fatal("expected an error for a missing key file")
+	}
+}
+
+func TestPasswordClientConfig(t *testing.T) {
+	auth := Password("git", "secret")
+
+	pw, ok := auth.(*PasswordAuth)
+	if !ok {
+		t.Fatalf("expected *PasswordAuth, got %T", auth)
+	}
+
+	cfg := pw.clientConfig()
+	if cfg.User != "git" {
+		t.Fatalf("expected user %q, got %q", "git", cfg.User)
+	}
+
+	if len(cfg.Auth) != 1 {
+		t.Fatalf("expected exactly one ssh.AuthMethod, got %d", len(cfg.Auth))
+	}
+}
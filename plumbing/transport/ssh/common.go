@@ -19,27 +19,115 @@ var (
 	ErrNotConnected                       = errors.New("not connected")
 	ErrUploadPackAnswerFormat             = errors.New("git-upload-pack bad answer format")
 	ErrUnsupportedVCS                     = errors.New("only git is supported")
-	ErrUnsupportedRepo                    = errors.New("only github.com is supported")
 )
 
-type Client struct{}
+// defaultSSHCommand builds the remote command the way the git CLI itself
+// does: the command name followed by the repository path, single-quoted.
+// It works against any server speaking the git-upload-pack/git-receive-pack
+// protocol, not just github.com.
+func defaultSSHCommand(name, path string) string {
+	return fmt.Sprintf("%s '%s'", name, path)
+}
+
+// Client is a transport.Client for the SSH protocol, configurable with
+// ClientOption values. As returned by NewClient with no options it verifies
+// host keys against ~/.ssh/known_hosts, matching the behaviour of the `ssh`
+// CLI. The zero value of Client has strict host key checking off and
+// accepts any host key.
+type Client struct {
+	hostKeyCallback       ssh.HostKeyCallback
+	strictHostKeyChecking bool
+	userKnownHostsFile    string
+	sshCommand            func(name, path string) string
+	env                   map[string]string
+}
+
+// ClientOption configures a Client returned by NewClient.
+type ClientOption func(*Client)
+
+// WithHostKeyCallback overrides host key verification entirely, e.g. with
+// a callback backed by a pinned key or a custom trust store.
+func WithHostKeyCallback(cb ssh.HostKeyCallback) ClientOption {
+	return func(c *Client) { c.hostKeyCallback = cb }
+}
+
+// WithStrictHostKeyChecking disables host key verification when strict is
+// false, equivalent to `ssh -o StrictHostKeyChecking=no`. It is enabled by
+// default; turning it off is an explicit, deliberate opt-in to insecurity.
+func WithStrictHostKeyChecking(strict bool) ClientOption {
+	return func(c *Client) { c.strictHostKeyChecking = strict }
+}
+
+// WithUserKnownHostsFile overrides the known_hosts file used for host key
+// verification, equivalent to `ssh -o UserKnownHostsFile=path`. Defaults to
+// DefaultKnownHostsFile.
+func WithUserKnownHostsFile(path string) ClientOption {
+	return func(c *Client) { c.userKnownHostsFile = path }
+}
+
+// WithSSHCommand overrides how the remote git-upload-pack/git-receive-pack
+// command is built from the command name ("git-upload-pack" or
+// "git-receive-pack") and the endpoint's repository path. Use it to talk to
+// a host that wraps those commands, e.g. Gitea's "gitea-serv" or a shim
+// that injects environment variables of its own.
+func WithSSHCommand(fn func(name, path string) string) ClientOption {
+	return func(c *Client) { c.sshCommand = fn }
+}
+
+// WithEnv sets environment variables to forward to the remote command,
+// equivalent to `ssh -o SendEnv=...`. The server must be configured to
+// accept them (AcceptEnv); servers that don't are left untouched since
+// Setenv failures are ignored, matching how the `ssh` CLI treats them.
+func WithEnv(env map[string]string) ClientOption {
+	return func(c *Client) { c.env = env }
+}
 
 var DefaultClient = NewClient()
 
-func NewClient() transport.Client {
-	return &Client{}
+func NewClient(opts ...ClientOption) transport.Client {
+	c := &Client{strictHostKeyChecking: true, sshCommand: defaultSSHCommand}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 func (c *Client) NewFetchPackSession(ep transport.Endpoint) (
 	transport.FetchPackSession, error) {
 
-	return newFetchPackSession(ep)
+	s, err := newFetchPackSession(ep)
+	if err != nil {
+		return nil, err
+	}
+
+	configureSession(s, c)
+	return s, nil
 }
 
 func (c *Client) NewSendPackSession(ep transport.Endpoint) (
 	transport.SendPackSession, error) {
 
-	return newSendPackSession(ep)
+	s, err := newSendPackSession(ep)
+	if err != nil {
+		return nil, err
+	}
+
+	configureSession(s, c)
+	return s, nil
+}
+
+// configurable is implemented by *session (and anything embedding it, such
+// as the fetch/send pack session types), letting Client hand its options
+// down without either side knowing the other's concrete type.
+type configurable interface {
+	configure(c *Client)
+}
+
+func configureSession(v interface{}, c *Client) {
+	if cs, ok := v.(configurable); ok {
+		cs.configure(c)
+	}
 }
 
 type session struct {
@@ -51,6 +139,32 @@ type session struct {
 	stdout      io.Reader
 	sessionDone chan error
 	auth        AuthMethod
+
+	hostKeyCallback       ssh.HostKeyCallback
+	strictHostKeyChecking bool
+	userKnownHostsFile    string
+	sshCommand            func(name, path string) string
+	env                   map[string]string
+}
+
+func (s *session) configure(c *Client) {
+	s.hostKeyCallback = c.hostKeyCallback
+	s.strictHostKeyChecking = c.strictHostKeyChecking
+	s.userKnownHostsFile = c.userKnownHostsFile
+	s.sshCommand = c.sshCommand
+	s.env = c.env
+}
+
+// command builds the remote command for name ("git-upload-pack" or
+// "git-receive-pack") against this session's endpoint, honoring any
+// WithSSHCommand override.
+func (s *session) command(name string) string {
+	sshCommand := s.sshCommand
+	if sshCommand == nil {
+		sshCommand = defaultSSHCommand
+	}
+
+	return sshCommand(name, s.endpoint.Path)
 }
 
 func (s *session) SetAuth(auth transport.AuthMethod) error {
@@ -86,8 +200,15 @@ func (s *session) connect() error {
 		return err
 	}
 
-	var err error
-	s.client, err = ssh.Dial("tcp", s.getHostWithPort(), s.auth.clientConfig())
+	hostKeyCallback, err := s.resolveHostKeyCallback()
+	if err != nil {
+		return fmt.Errorf("cannot resolve host key: %s", err)
+	}
+
+	config := s.auth.clientConfig()
+	config.HostKeyCallback = hostKeyCallback
+
+	s.client, err = ssh.Dial("tcp", s.getHostWithPort(), config)
 	if err != nil {
 		return err
 	}
@@ -101,19 +222,60 @@ func (s *session) connect() error {
 	return nil
 }
 
+// resolveHostKeyCallback picks the ssh.HostKeyCallback to verify the
+// server with: an explicit WithHostKeyCallback override, an explicit
+// opt-out via WithStrictHostKeyChecking(false), or otherwise the configured
+// (or default) known_hosts file, exactly as the `ssh` CLI would.
+func (s *session) resolveHostKeyCallback() (ssh.HostKeyCallback, error) {
+	if s.hostKeyCallback != nil {
+		return s.hostKeyCallback, nil
+	}
+
+	if !s.strictHostKeyChecking {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	var files []string
+	if s.userKnownHostsFile != "" {
+		files = []string{s.userKnownHostsFile}
+	}
+
+	return KnownHostsCallback(files...)
+}
+
 func (s *session) getHostWithPort() string {
-	host := s.endpoint.Host
-	if strings.Index(s.endpoint.Host, ":") == -1 {
-		host += ":22"
+	host := newSSHConfig(s.endpoint.Host).hostName()
+	if strings.Index(host, ":") == -1 {
+		port := newSSHConfig(s.endpoint.Host).port()
+		if port == "" {
+			port = "22"
+		}
+
+		host += ":" + port
 	}
 
 	return host
 }
 
 func (s *session) setAuthFromEndpoint() error {
-	var u string
-	if info := s.endpoint.User; info != nil {
-		u = info.Username()
+	if s.auth != nil {
+		return nil
+	}
+
+	cfg := newSSHConfig(s.endpoint.Host)
+
+	u := cfg.user()
+	if u == "" {
+		if info := s.endpoint.User; info != nil {
+			u = info.Username()
+		}
+	}
+
+	if file := cfg.identityFile(); file != "" {
+		if auth, err := PubKeyFromFile(u, file, ""); err == nil {
+			s.auth = auth
+			return nil
+		}
 	}
 
 	var err error
@@ -138,6 +300,12 @@ func (s *session) openSSHSession() error {
 		return fmt.Errorf("cannot pipe remote stdout: %s", err)
 	}
 
+	// Best-effort: a server that hasn't AcceptEnv'd these names will reject
+	// them, same as the `ssh` CLI, and we don't treat that as fatal.
+	for k, v := range s.env {
+		_ = s.session.Setenv(k, v)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,64 @@
+package ssh
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestResolveHostKeyCallbackOverride(t *testing.T) {
+	called := false
+	override := ssh.HostKeyCallback(func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		called = true
+		return nil
+	})
+
+	s := &session{hostKeyCallback: override}
+
+	cb, err := s.resolveHostKeyCallback()
+	if err != nil {
+		t.Fatalf("resolveHostKeyCallback: %s", err)
+	}
+
+	if err := cb("host", nil, nil); err != nil {
+		t.Fatalf("unexpected error from the overridden callback: %s", err)
+	}
+
+	if !called {
+		t.Fatal("expected the overridden HostKeyCallback to be used")
+	}
+}
+
+func TestResolveHostKeyCallbackInsecure(t *testing.T) {
+	s := &session{strictHostKeyChecking: false}
+
+	cb, err := s.resolveHostKeyCallback()
+	if err != nil {
+		t.Fatalf("resolveHostKeyCallback: %s", err)
+	}
+
+	if err := cb("host", nil, nil); err != nil {
+		t.Fatalf("expected the insecure callback to accept any key: %s", err)
+	}
+}
+
+func TestResolveHostKeyCallbackFailsClosed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "known-hosts")
+	if err != nil {
+		t.Fatalf("creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := &session{
+		strictHostKeyChecking: true,
+		userKnownHostsFile:    filepath.Join(dir, "does-not-exist"),
+	}
+
+	if _, err := s.resolveHostKeyCallback(); err == nil {
+		t.Fatal("expected strict checking with a missing known_hosts file to error, not fall back to trusting the host")
+	}
+}
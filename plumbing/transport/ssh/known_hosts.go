@@ -0,0 +1,35 @@
+package ssh
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// DefaultKnownHostsFile is where KnownHostsCallback looks for host keys when
+// no file is given explicitly, mirroring the OpenSSH client default.
+func DefaultKnownHostsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".ssh", "known_hosts")
+}
+
+// KnownHostsCallback returns an ssh.HostKeyCallback that verifies host keys
+// against the given known_hosts files, falling back to
+// DefaultKnownHostsFile when no files are given. It fails closed: if any of
+// the files cannot be read, it returns an error rather than a callback that
+// silently accepts any host key.
+func KnownHostsCallback(files ...string) (ssh.HostKeyCallback, error) {
+	if len(files) == 0 {
+		if f := DefaultKnownHostsFile(); f != "" {
+			files = []string{f}
+		}
+	}
+
+	return knownhosts.New(files...)
+}
@@ -0,0 +1,69 @@
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func genKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %s", err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("converting key: %s", err)
+	}
+
+	return sshPub
+}
+
+func TestKnownHostsCallback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "known-hosts")
+	if err != nil {
+		t.Fatalf("creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	host := "example.com:22"
+	good := genKey(t)
+	bad := genKey(t)
+
+	path := filepath.Join(dir, "known_hosts")
+	line := knownhosts.Line([]string{host}, good)
+	if err := ioutil.WriteFile(path, []byte(line+"\n"), 0600); err != nil {
+		t.Fatalf("writing known_hosts: %s", err)
+	}
+
+	cb, err := KnownHostsCallback(path)
+	if err != nil {
+		t.Fatalf("KnownHostsCallback: %s", err)
+	}
+
+	addr := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 22}
+
+	if err := cb(host, addr, good); err != nil {
+		t.Fatalf("expected the known, matching key to be accepted: %s", err)
+	}
+
+	if err := cb(host, addr, bad); err == nil {
+		t.Fatal("expected a mismatched host key to be rejected")
+	}
+}
+
+func TestKnownHostsCallbackMissingFile(t *testing.T) {
+	if _, err := KnownHostsCallback(filepath.Join("testdata", "does-not-exist")); err == nil {
+		t.Fatal("expected an error for a known_hosts file that doesn't exist")
+	}
+}
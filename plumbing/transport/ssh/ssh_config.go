@@ -0,0 +1,55 @@
+package ssh
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kevinburke/ssh_config"
+)
+
+// sshConfig resolves the Host/HostName/Port/User/IdentityFile directives a
+// plain `ssh` CLI would apply for a given host alias, by reading
+// ~/.ssh/config (and /etc/ssh/ssh_config, via ssh_config.Get's fallback).
+// It lets getHostWithPort and setAuthFromEndpoint agree with what a user
+// would get running `ssh host`.
+type sshConfig struct {
+	host string
+}
+
+func newSSHConfig(host string) *sshConfig {
+	return &sshConfig{host: host}
+}
+
+func (c *sshConfig) hostName() string {
+	if v := ssh_config.Get(c.host, "HostName"); v != "" {
+		return v
+	}
+
+	return c.host
+}
+
+func (c *sshConfig) port() string {
+	return ssh_config.Get(c.host, "Port")
+}
+
+func (c *sshConfig) user() string {
+	return ssh_config.Get(c.host, "User")
+}
+
+func (c *sshConfig) identityFile() string {
+	file := ssh_config.Get(c.host, "IdentityFile")
+	if file == "" {
+		return ""
+	}
+
+	if len(file) > 0 && file[0] == '~' {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return file
+		}
+
+		file = filepath.Join(home, file[1:])
+	}
+
+	return file
+}
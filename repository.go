@@ -0,0 +1,28 @@
+package git
+
+import (
+	"gopkg.in/src-d/go-git.v2/core"
+	"gopkg.in/src-d/go-git.v2/storage"
+)
+
+// Repository gives access to the trees, blobs and commits of a single git
+// repository, reading them through Storage.
+type Repository struct {
+	Storage core.ObjectStorage
+}
+
+// NewRepository opens a Repository whose objects live behind url, e.g.
+// "s3://bucket/prefix" or "gs://bucket/prefix" (see storage.Open for the
+// supported schemes). Objects fetched from the remote backend are kept in
+// a bounded in-memory LRU so that repeated tree walks, such as Files or
+// Diff, don't re-fetch the same blob or tree on every traversal.
+func NewRepository(url string) (*Repository, error) {
+	backend, err := storage.Open(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Repository{
+		Storage: storage.ObjectStorage{Backend: storage.NewCache(backend, storage.DefaultCacheSize)},
+	}, nil
+}
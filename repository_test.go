@@ -0,0 +1,62 @@
+package git
+
+import (
+	"net/url"
+	"testing"
+
+	"gopkg.in/src-d/go-git.v2/core"
+	"gopkg.in/src-d/go-git.v2/storage"
+)
+
+// testBackend is a storage.Backend backed by a plain map, registered under
+// the "memtest" scheme so NewRepository can be exercised without a real
+// remote object store.
+type testBackend map[core.Hash]core.Object
+
+func (b testBackend) Get(hash core.Hash) (core.Object, bool, error) {
+	obj, ok := b[hash]
+	return obj, ok, nil
+}
+
+func (b testBackend) Put(obj core.Object) error {
+	b[obj.Hash()] = obj
+	return nil
+}
+
+func (b testBackend) Has(hash core.Hash) bool {
+	_, ok := b[hash]
+	return ok
+}
+
+var memtestBackend = testBackend{}
+
+func init() {
+	storage.Register("memtest", func(u *url.URL) (storage.Backend, error) {
+		return memtestBackend, nil
+	})
+}
+
+func TestNewRepositoryWiresStorage(t *testing.T) {
+	hash := testHash(1)
+	memtestBackend.Put(core.NewObject(hash, core.BlobObject, []byte("hello")))
+
+	repo, err := NewRepository("memtest://bucket/prefix")
+	if err != nil {
+		t.Fatalf("NewRepository: %s", err)
+	}
+
+	obj, ok := repo.Storage.Get(hash)
+	if !ok {
+		t.Fatal("expected the object seeded into the registered backend to be reachable through Repository.Storage")
+	}
+
+	if obj.Type() != core.BlobObject {
+		t.Fatalf("expected a blob, got %s", obj.Type())
+	}
+}
+
+func TestNewRepositoryUnsupportedScheme(t *testing.T) {
+	if _, err := NewRepository("ftp://example.com/bucket"); err != storage.ErrUnsupportedScheme {
+		t.Fatalf("expected ErrUnsupportedScheme, got %v", err)
+	}
+}
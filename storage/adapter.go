@@ -0,0 +1,24 @@
+package storage
+
+import "gopkg.in/src-d/go-git.v2/core"
+
+// ObjectStorage adapts a Backend to the core.ObjectStorage interface
+// expected by Repository, so that a remote-backed Backend (optionally
+// wrapped in a Cache) can be dropped straight into Repository.Storage:
+//
+//	backend, err := storage.Open("s3://my-bucket/objects")
+//	repo.Storage = storage.ObjectStorage{Backend: storage.NewCache(backend, 0)}
+type ObjectStorage struct {
+	Backend
+}
+
+// Get satisfies core.ObjectStorage, discarding Backend errors as a miss
+// since that interface has no room for one.
+func (s ObjectStorage) Get(hash core.Hash) (core.Object, bool) {
+	obj, ok, err := s.Backend.Get(hash)
+	if err != nil {
+		return nil, false
+	}
+
+	return obj, ok
+}
@@ -0,0 +1,59 @@
+// Package storage defines a pluggable backend for reading and writing the
+// objects (blobs, trees, commits and tags) that make up a repository. The
+// default, implicit backend keeps every object in memory; the
+// implementations in this package let a repository keep its objects in
+// remote object storage instead, fetching them on demand.
+package storage
+
+import (
+	"errors"
+	"net/url"
+
+	"gopkg.in/src-d/go-git.v2/core"
+)
+
+// ErrUnsupportedScheme is returned by Open when no Backend has been
+// registered for the URL's scheme.
+var ErrUnsupportedScheme = errors.New("storage: unsupported scheme")
+
+// Backend is a source and sink of core.Object values, typically backed by a
+// remote object store such as Google Cloud Storage or Amazon S3.
+type Backend interface {
+	// Get retrieves the object identified by hash. The second return value
+	// reports whether the object was found.
+	Get(hash core.Hash) (obj core.Object, ok bool, err error)
+	// Put stores obj, keyed by its own hash.
+	Put(obj core.Object) error
+	// Has reports whether an object with the given hash is present,
+	// without fetching its contents.
+	Has(hash core.Hash) bool
+}
+
+// Opener builds a Backend from the scheme-specific part of a URL, e.g. the
+// bucket and key prefix encoded in "s3://bucket/prefix".
+type Opener func(u *url.URL) (Backend, error)
+
+var openers = map[string]Opener{}
+
+// Register makes an Opener available under the given URL scheme. It is
+// meant to be called from the init function of a Backend implementation,
+// such as the "gs" and "s3" backends in gcs.go and s3.go.
+func Register(scheme string, opener Opener) {
+	openers[scheme] = opener
+}
+
+// Open returns the Backend registered for rawurl's scheme, e.g.
+// Open("s3://my-bucket/objects") or Open("gs://my-bucket/objects").
+func Open(rawurl string) (Backend, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+
+	opener, ok := openers[u.Scheme]
+	if !ok {
+		return nil, ErrUnsupportedScheme
+	}
+
+	return opener(u)
+}
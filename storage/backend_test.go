@@ -0,0 +1,16 @@
+package storage
+
+import "testing"
+
+func TestOpenUnsupportedScheme(t *testing.T) {
+	_, err := Open("ftp://example.com/bucket")
+	if err != ErrUnsupportedScheme {
+		t.Fatalf("expected ErrUnsupportedScheme, got %v", err)
+	}
+}
+
+func TestOpenInvalidURL(t *testing.T) {
+	if _, err := Open("://"); err == nil {
+		t.Fatal("expected an error for an unparsable URL")
+	}
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"container/list"
+	"sync"
+
+	"gopkg.in/src-d/go-git.v2/core"
+)
+
+// DefaultCacheSize is the number of objects kept in memory by NewCache when
+// no explicit size is requested.
+const DefaultCacheSize = 1000
+
+// Cache wraps a Backend with a bounded, in-memory LRU of recently used
+// objects, so that repeatedly-walked trees don't re-fetch the same blobs
+// and trees from a remote Backend on every traversal.
+type Cache struct {
+	backend Backend
+	size    int
+
+	mu      sync.Mutex
+	entries map[core.Hash]*list.Element
+	order   *list.List
+}
+
+type cacheEntry struct {
+	hash core.Hash
+	obj  core.Object
+}
+
+// NewCache returns a Backend that serves Get calls out of an LRU of at most
+// size objects, falling back to backend on a miss.
+func NewCache(backend Backend, size int) *Cache {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+
+	return &Cache{
+		backend: backend,
+		size:    size,
+		entries: make(map[core.Hash]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get implements Backend, consulting the LRU before falling back to the
+// wrapped backend.
+func (c *Cache) Get(hash core.Hash) (core.Object, bool, error) {
+	c.mu.Lock()
+	if el, ok := c.entries[hash]; ok {
+		c.order.MoveToFront(el)
+		obj := el.Value.(*cacheEntry).obj
+		c.mu.Unlock()
+		return obj, true, nil
+	}
+	c.mu.Unlock()
+
+	obj, ok, err := c.backend.Get(hash)
+	if err != nil || !ok {
+		return obj, ok, err
+	}
+
+	c.add(hash, obj)
+	return obj, true, nil
+}
+
+// Put implements Backend, writing through to the wrapped backend and
+// priming the cache with the stored object.
+func (c *Cache) Put(obj core.Object) error {
+	if err := c.backend.Put(obj); err != nil {
+		return err
+	}
+
+	c.add(obj.Hash(), obj)
+	return nil
+}
+
+// Has implements Backend.
+func (c *Cache) Has(hash core.Hash) bool {
+	c.mu.Lock()
+	_, ok := c.entries[hash]
+	c.mu.Unlock()
+	if ok {
+		return true
+	}
+
+	return c.backend.Has(hash)
+}
+
+func (c *Cache) add(hash core.Hash, obj core.Object) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[hash]; ok {
+		c.order.MoveToFront(el)
+		el.Value.(*cacheEntry).obj = obj
+		return
+	}
+
+	el := c.order.PushFront(&cacheEntry{hash: hash, obj: obj})
+	c.entries[hash] = el
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).hash)
+	}
+}
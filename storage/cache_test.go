@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"testing"
+
+	"gopkg.in/src-d/go-git.v2/core"
+)
+
+// countingBackend is an in-memory Backend that counts Get calls, so tests
+// can tell whether Cache served a request from memory or hit it.
+type countingBackend struct {
+	objects map[core.Hash]core.Object
+	gets    int
+}
+
+func newCountingBackend() *countingBackend {
+	return &countingBackend{objects: make(map[core.Hash]core.Object)}
+}
+
+func (b *countingBackend) Get(hash core.Hash) (core.Object, bool, error) {
+	b.gets++
+	obj, ok := b.objects[hash]
+	return obj, ok, nil
+}
+
+func (b *countingBackend) Put(obj core.Object) error {
+	b.objects[obj.Hash()] = obj
+	return nil
+}
+
+func (b *countingBackend) Has(hash core.Hash) bool {
+	_, ok := b.objects[hash]
+	return ok
+}
+
+func putObject(t *testing.T, backend *countingBackend, hash core.Hash, content []byte) {
+	t.Helper()
+
+	obj, err := core.NewObjectFromBytes(hash, content)
+	if err != nil {
+		t.Fatalf("building test object: %s", err)
+	}
+
+	if err := backend.Put(obj); err != nil {
+		t.Fatalf("seeding backend: %s", err)
+	}
+}
+
+func TestCacheServesRepeatedGetsFromMemory(t *testing.T) {
+	backend := newCountingBackend()
+	hash := core.Hash{1}
+	putObject(t, backend, hash, []byte("hello"))
+
+	cache := NewCache(backend, 10)
+
+	if _, _, err := cache.Get(hash); err != nil {
+		t.Fatalf("first Get: %s", err)
+	}
+	if _, _, err := cache.Get(hash); err != nil {
+		t.Fatalf("second Get: %s", err)
+	}
+
+	if backend.gets != 1 {
+		t.Fatalf("expected the backend to be hit once, got %d", backend.gets)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	backend := newCountingBackend()
+	for i := byte(0); i < 3; i++ {
+		putObject(t, backend, core.Hash{i}, []byte{i})
+	}
+
+	cache := NewCache(backend, 2)
+
+	for i := byte(0); i < 3; i++ {
+		if _, _, err := cache.Get(core.Hash{i}); err != nil {
+			t.Fatalf("warming Get(%d): %s", i, err)
+		}
+	}
+
+	backend.gets = 0
+
+	// Hash{0} was the least recently used of the three and should have
+	// been evicted once the cache (size 2) filled up.
+	if _, _, err := cache.Get(core.Hash{0}); err != nil {
+		t.Fatalf("Get(0): %s", err)
+	}
+	if backend.gets != 1 {
+		t.Fatal("expected the evicted entry to require a re-fetch from the backend")
+	}
+
+	// Hash{2} should still be in the cache.
+	backend.gets = 0
+	if _, _, err := cache.Get(core.Hash{2}); err != nil {
+		t.Fatalf("Get(2): %s", err)
+	}
+	if backend.gets != 0 {
+		t.Fatal("expected the still-cached entry to be served from memory")
+	}
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/cloud"
+	"google.golang.org/cloud/storage"
+
+	"gopkg.in/src-d/go-git.v2/core"
+)
+
+func init() {
+	Register("gs", openGCS)
+}
+
+// gcsBackend is a Backend that keeps objects under a prefix in a single
+// Google Cloud Storage bucket, addressed as "gs://bucket/prefix".
+type gcsBackend struct {
+	ctx    context.Context
+	client *storage.Client
+	bucket string
+	prefix string
+}
+
+func openGCS(u *url.URL) (Backend, error) {
+	ctx := context.Background()
+
+	client, err := storage.NewClient(ctx, cloud.WithScopes(storage.ScopeReadWrite))
+	if err != nil {
+		return nil, err
+	}
+
+	return &gcsBackend{
+		ctx:    ctx,
+		client: client,
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+func (b *gcsBackend) key(hash core.Hash) string {
+	if b.prefix == "" {
+		return hash.String()
+	}
+
+	return b.prefix + "/" + hash.String()
+}
+
+func (b *gcsBackend) Get(hash core.Hash) (core.Object, bool, error) {
+	r, err := b.client.Bucket(b.bucket).Object(b.key(hash)).NewReader(b.ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer r.Close()
+
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, false, err
+	}
+
+	obj, err := core.NewObjectFromBytes(hash, content)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return obj, true, nil
+}
+
+func (b *gcsBackend) Put(obj core.Object) error {
+	w := b.client.Bucket(b.bucket).Object(b.key(obj.Hash())).NewWriter(b.ctx)
+	if _, err := io.Copy(w, obj.Reader()); err != nil {
+		_ = w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+func (b *gcsBackend) Has(hash core.Hash) bool {
+	_, err := b.client.Bucket(b.bucket).Object(b.key(hash)).Attrs(b.ctx)
+	return err == nil
+}
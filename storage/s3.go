@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"gopkg.in/src-d/go-git.v2/core"
+)
+
+func init() {
+	Register("s3", openS3)
+}
+
+// s3Backend is a Backend that keeps objects under a prefix in a single
+// Amazon S3 bucket, addressed as "s3://bucket/prefix".
+type s3Backend struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+func openS3(u *url.URL) (Backend, error) {
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Backend{
+		client: s3.New(sess),
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+func (b *s3Backend) key(hash core.Hash) string {
+	if b.prefix == "" {
+		return hash.String()
+	}
+
+	return b.prefix + "/" + hash.String()
+}
+
+func (b *s3Backend) Get(hash core.Hash) (core.Object, bool, error) {
+	out, err := b.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key(hash)),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer out.Body.Close()
+
+	content, err := ioutil.ReadAll(out.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	obj, err := core.NewObjectFromBytes(hash, content)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return obj, true, nil
+}
+
+func (b *s3Backend) Put(obj core.Object) error {
+	content, err := ioutil.ReadAll(obj.Reader())
+	if err != nil {
+		return err
+	}
+
+	_, err = b.client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key(obj.Hash())),
+		Body:   bytes.NewReader(content),
+	})
+
+	return err
+}
+
+func (b *s3Backend) Has(hash core.Hash) bool {
+	_, err := b.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key(hash)),
+	})
+
+	return err == nil
+}
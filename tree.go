@@ -4,10 +4,14 @@ import (
 	"bufio"
 	"errors"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+
+	"gopkg.in/src-d/go-billy.v4"
 
 	"gopkg.in/src-d/go-git.v2/core"
 )
@@ -31,6 +35,72 @@ type TreeEntry struct {
 // New errors defined by this package.
 var ErrFileNotFound = errors.New("file not found")
 
+// The git tree modes that need special handling during Checkout, stored
+// verbatim (not as real os.FileMode bits) in TreeEntry.Mode - see Decode.
+const (
+	SymlinkMode   os.FileMode = 0120000
+	SubmoduleMode os.FileMode = 0160000
+)
+
+// Checkout materializes the tree into fs, rooted at path, creating
+// directories as needed, following the existing dir/walkEntries logic to
+// recurse into subtrees. Symlinks (SymlinkMode) are written as symlinks
+// pointing at their stored target, and gitlinks (SubmoduleMode, i.e.
+// submodules) are skipped since their content lives in another repository.
+func (t *Tree) Checkout(fs billy.Filesystem, path string) error {
+	for name, entry := range t.Entries {
+		if err := t.checkoutEntry(fs, filepath.Join(path, name), entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *Tree) checkoutEntry(fs billy.Filesystem, path string, entry TreeEntry) error {
+	if entry.Mode == SubmoduleMode {
+		return nil
+	}
+
+	obj, ok := t.r.Storage.Get(entry.Hash)
+	if !ok {
+		return nil // a git submodule with no local object
+	}
+
+	if obj.Type() == core.TreeObject {
+		tree := &Tree{r: t.r}
+		tree.Decode(obj)
+		return tree.Checkout(fs, path)
+	}
+
+	blob := &Blob{}
+	blob.Decode(obj)
+
+	if entry.Mode == SymlinkMode {
+		target, err := ioutil.ReadAll(blob.Reader())
+		if err != nil {
+			return err
+		}
+
+		return fs.Symlink(string(target), path)
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := fs.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	f, err := fs.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, entry.Mode.Perm())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, blob.Reader())
+	return err
+}
+
 // File returns the hash of the file identified by the `path` argument.
 // The path is interpreted as relative to the tree receiver.
 func (t *Tree) File(path string) (*File, error) {
@@ -112,40 +182,65 @@ func (t *Tree) entry(baseName string) (*TreeEntry, error) {
 	return &entry, nil
 }
 
+// filesWorkers bounds the number of goroutines walkEntries uses to fetch
+// blobs concurrently, so that a remote storage.Backend (where round-trips,
+// not CPU, dominate) doesn't serialize a whole tree walk behind one fetch
+// at a time.
+const filesWorkers = 8
+
 func (t *Tree) Files() chan *File {
 	ch := make(chan *File, 1)
+	sem := make(chan struct{}, filesWorkers)
 
 	go func() {
-		defer func() { close(ch) }()
-		t.walkEntries("", ch)
+		var wg sync.WaitGroup
+		defer func() {
+			wg.Wait()
+			close(ch)
+		}()
+		t.walkEntries("", ch, sem, &wg)
 	}()
 
 	return ch
 }
 
-func (t *Tree) walkEntries(base string, ch chan *File) {
+func (t *Tree) walkEntries(base string, ch chan *File, sem chan struct{}, wg *sync.WaitGroup) {
 	for _, entry := range t.Entries {
-		obj, ok := t.r.Storage.Get(entry.Hash)
-		if !ok {
-			continue // ignore entries without hash (= submodule dirs)
-		}
+		entry := entry
 
-		if obj.Type() == core.TreeObject {
-			tree := &Tree{r: t.r}
-			tree.Decode(obj)
-			tree.walkEntries(filepath.Join(base, entry.Name), ch)
-			continue
-		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
 
-		blob := &Blob{}
-		blob.Decode(obj)
+			obj, ok := t.r.Storage.Get(entry.Hash)
+			if !ok {
+				return // ignore entries without hash (= submodule dirs)
+			}
 
-		ch <- &File{
-			Name:   filepath.Join(base, entry.Name),
-			Reader: blob.Reader(),
-			Hash:   entry.Hash,
-			Size:   blob.Size,
-		}
+			if obj.Type() == core.TreeObject {
+				tree := &Tree{r: t.r}
+				tree.Decode(obj)
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					tree.walkEntries(filepath.Join(base, entry.Name), ch, sem, wg)
+				}()
+				return
+			}
+
+			blob := &Blob{}
+			blob.Decode(obj)
+
+			ch <- &File{
+				Name:   filepath.Join(base, entry.Name),
+				Reader: blob.Reader(),
+				Hash:   entry.Hash,
+				Size:   blob.Size,
+			}
+		}()
 	}
 }
 
@@ -0,0 +1,133 @@
+package git
+
+import (
+	"os"
+	"testing"
+)
+
+// TestCheckoutModeConstants guards the raw git mode values Checkout
+// compares TreeEntry.Mode against - a typo here would silently turn every
+// symlink or submodule entry into a regular file checkout.
+func TestCheckoutModeConstants(t *testing.T) {
+	if SymlinkMode != 0120000 {
+		t.Fatalf("expected SymlinkMode to be the git symlink mode 0120000, got %o", SymlinkMode)
+	}
+
+	if SubmoduleMode != 0160000 {
+		t.Fatalf("expected SubmoduleMode to be the git gitlink mode 0160000, got %o", SubmoduleMode)
+	}
+}
+
+func TestCheckoutPreservesFileMode(t *testing.T) {
+	storage := memStorage{}
+	blobHash := testHash(1)
+	storage.putBlob(blobHash, []byte("#!/bin/sh\necho hi\n"))
+
+	tree := &Tree{
+		r: &Repository{Storage: storage},
+		Entries: map[string]TreeEntry{
+			"run.sh": {Name: "run.sh", Mode: 0100755, Hash: blobHash},
+		},
+	}
+
+	fs := newMemFS()
+	if err := tree.Checkout(fs, ""); err != nil {
+		t.Fatalf("Checkout: %s", err)
+	}
+
+	f, ok := fs.files["run.sh"]
+	if !ok {
+		t.Fatal("expected run.sh to be written")
+	}
+
+	if f.mode.Perm() != 0755 {
+		t.Fatalf("expected mode 0755, got %o", f.mode.Perm())
+	}
+
+	if f.buf.String() != "#!/bin/sh\necho hi\n" {
+		t.Fatalf("unexpected content %q", f.buf.String())
+	}
+}
+
+func TestCheckoutWritesSymlinks(t *testing.T) {
+	storage := memStorage{}
+	blobHash := testHash(1)
+	storage.putBlob(blobHash, []byte("target.txt"))
+
+	tree := &Tree{
+		r: &Repository{Storage: storage},
+		Entries: map[string]TreeEntry{
+			"link.txt": {Name: "link.txt", Mode: SymlinkMode, Hash: blobHash},
+		},
+	}
+
+	fs := newMemFS()
+	if err := tree.Checkout(fs, ""); err != nil {
+		t.Fatalf("Checkout: %s", err)
+	}
+
+	target, ok := fs.symlinks["link.txt"]
+	if !ok {
+		t.Fatal("expected link.txt to be written as a symlink")
+	}
+
+	if target != "target.txt" {
+		t.Fatalf("expected symlink target %q, got %q", "target.txt", target)
+	}
+
+	if _, ok := fs.files["link.txt"]; ok {
+		t.Fatal("expected link.txt not to be written as a regular file")
+	}
+}
+
+func TestCheckoutSkipsSubmodules(t *testing.T) {
+	storage := memStorage{}
+
+	tree := &Tree{
+		r: &Repository{Storage: storage},
+		Entries: map[string]TreeEntry{
+			"vendor/lib": {Name: "vendor/lib", Mode: SubmoduleMode, Hash: testHash(9)},
+		},
+	}
+
+	fs := newMemFS()
+	if err := tree.Checkout(fs, ""); err != nil {
+		t.Fatalf("Checkout: %s", err)
+	}
+
+	if len(fs.files) != 0 || len(fs.symlinks) != 0 {
+		t.Fatal("expected a submodule entry to be skipped entirely")
+	}
+}
+
+func TestCheckoutRecursesIntoSubtrees(t *testing.T) {
+	storage := memStorage{}
+	blobHash := testHash(1)
+	storage.putBlob(blobHash, []byte("hello"))
+
+	subtreeHash := testHash(2)
+	storage.putTree(subtreeHash, map[string]TreeEntry{
+		"b.txt": {Name: "b.txt", Mode: 0100644, Hash: blobHash},
+	})
+
+	tree := &Tree{
+		r: &Repository{Storage: storage},
+		Entries: map[string]TreeEntry{
+			"sub": {Name: "sub", Mode: os.ModeDir, Hash: subtreeHash},
+		},
+	}
+
+	fs := newMemFS()
+	if err := tree.Checkout(fs, ""); err != nil {
+		t.Fatalf("Checkout: %s", err)
+	}
+
+	f, ok := fs.files["sub/b.txt"]
+	if !ok {
+		t.Fatal("expected sub/b.txt to be written")
+	}
+
+	if f.buf.String() != "hello" {
+		t.Fatalf("unexpected content %q", f.buf.String())
+	}
+}
@@ -0,0 +1,23 @@
+package git
+
+import "gopkg.in/src-d/go-billy.v4"
+
+// Worktree ties a Repository to a billy.Filesystem that its trees can be
+// checked out onto. Using billy.Filesystem instead of the os package keeps
+// Checkout working the same way against a real directory, an in-memory
+// filesystem, or a chroot, and gives us a seam to hang Status and Add off
+// of later.
+type Worktree struct {
+	r  *Repository
+	fs billy.Filesystem
+}
+
+// Worktree returns a Worktree for this repository backed by fs.
+func (r *Repository) Worktree(fs billy.Filesystem) *Worktree {
+	return &Worktree{r: r, fs: fs}
+}
+
+// Checkout materializes tree onto the worktree's filesystem.
+func (w *Worktree) Checkout(tree *Tree) error {
+	return tree.Checkout(w.fs, "")
+}
@@ -0,0 +1,37 @@
+package git
+
+import "testing"
+
+func TestWorktreeCheckoutDelegatesToTree(t *testing.T) {
+	storage := memStorage{}
+	blobHash := testHash(1)
+	storage.putBlob(blobHash, []byte("hello"))
+
+	tree := &Tree{
+		r: &Repository{Storage: storage},
+		Entries: map[string]TreeEntry{
+			"a.txt": {Name: "a.txt", Mode: 0100644, Hash: blobHash},
+		},
+	}
+
+	fs := newMemFS()
+	repo := &Repository{Storage: storage}
+
+	w := repo.Worktree(fs)
+	if w.fs != fs {
+		t.Fatal("expected Worktree to keep the filesystem it was given")
+	}
+
+	if err := w.Checkout(tree); err != nil {
+		t.Fatalf("Checkout: %s", err)
+	}
+
+	f, ok := fs.files["a.txt"]
+	if !ok {
+		t.Fatal("expected a.txt to be written")
+	}
+
+	if f.buf.String() != "hello" {
+		t.Fatalf("unexpected content %q", f.buf.String())
+	}
+}